@@ -0,0 +1,196 @@
+package dal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Cond is a node in a predicate tree combined via AND/OR/NOT, rendered to
+// parameterized SQL by a Dialect. Build leaves with Q and combine them with
+// And/Or/Not.
+type Cond struct {
+	kind     string // "leaf", "and", "or", "not"
+	lookup   string
+	args     []interface{}
+	children []*Cond
+}
+
+// Q builds a leaf condition from a "field__lookup" expression, e.g.
+// Q("age__gte", 18) or Q("name", "bob") (lookup defaults to exact).
+func Q(lookup string, args ...interface{}) *Cond {
+	return &Cond{kind: "leaf", lookup: lookup, args: args}
+}
+
+// And combines c with other, true only when both hold.
+func (c *Cond) And(other *Cond) *Cond {
+	return &Cond{kind: "and", children: []*Cond{c, other}}
+}
+
+// Or combines c with other, true when either holds.
+func (c *Cond) Or(other *Cond) *Cond {
+	return &Cond{kind: "or", children: []*Cond{c, other}}
+}
+
+// Not negates c.
+func (c *Cond) Not() *Cond {
+	return &Cond{kind: "not", children: []*Cond{c}}
+}
+
+// lookupOps are the recognized "field__op" suffixes; anything else is taken
+// as a literal column name with an implicit "exact" op.
+var lookupOps = map[string]bool{
+	"exact": true, "iexact": true,
+	"contains": true, "icontains": true,
+	"startswith": true, "istartswith": true,
+	"endswith": true, "iendswith": true,
+	"gt": true, "gte": true, "lt": true, "lte": true,
+	"in": true, "between": true, "isnull": true,
+}
+
+func splitLookup(lookup string) (field, op string) {
+	if i := strings.LastIndex(lookup, "__"); i >= 0 && lookupOps[lookup[i+2:]] {
+		return lookup[:i], lookup[i+2:]
+	}
+	return lookup, "exact"
+}
+
+// toSQL renders c as parameterized SQL, consuming and advancing argIndex for
+// dialects (e.g. Postgres) whose placeholders are numbered.
+func (c *Cond) toSQL(d Dialect, argIndex *int) (string, []interface{}) {
+	switch c.kind {
+	case "not":
+		inner, args := c.children[0].toSQL(d, argIndex)
+		return "NOT (" + inner + ")", args
+	case "and", "or":
+		left, leftArgs := c.children[0].toSQL(d, argIndex)
+		right, rightArgs := c.children[1].toSQL(d, argIndex)
+		joiner := " AND "
+		if c.kind == "or" {
+			joiner = " OR "
+		}
+		return "(" + left + joiner + right + ")", append(leftArgs, rightArgs...)
+	default:
+		return c.renderLeaf(d, argIndex)
+	}
+}
+
+// constFalse is a leaf-level constant predicate, portable across every
+// supported dialect, used when a lookup cannot be rendered as given (e.g. a
+// missing argument or an empty "in" set) rather than emitting invalid SQL
+// or a mismatched placeholder/argument count.
+const constFalse = "1=0"
+
+func (c *Cond) renderLeaf(d Dialect, argIndex *int) (string, []interface{}) {
+	field, op := splitLookup(c.lookup)
+	next := func() string {
+		ph := d.Placeholder(*argIndex)
+		*argIndex++
+		return ph
+	}
+
+	switch op {
+	case "exact":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return field + " = " + next(), c.args
+	case "iexact":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return "LOWER(" + field + ") = LOWER(" + next() + ")", c.args
+	case "contains":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return field + " " + d.CaseSensitiveLikeOperator() + " " + next(), []interface{}{"%" + fmt.Sprint(c.args[0]) + "%"}
+	case "icontains":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return field + " " + d.LikeOperator() + " " + next(), []interface{}{"%" + fmt.Sprint(c.args[0]) + "%"}
+	case "startswith":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return field + " " + d.CaseSensitiveLikeOperator() + " " + next(), []interface{}{fmt.Sprint(c.args[0]) + "%"}
+	case "istartswith":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return field + " " + d.LikeOperator() + " " + next(), []interface{}{fmt.Sprint(c.args[0]) + "%"}
+	case "endswith":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return field + " " + d.CaseSensitiveLikeOperator() + " " + next(), []interface{}{"%" + fmt.Sprint(c.args[0])}
+	case "iendswith":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return field + " " + d.LikeOperator() + " " + next(), []interface{}{"%" + fmt.Sprint(c.args[0])}
+	case "gt":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return field + " > " + next(), c.args
+	case "gte":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return field + " >= " + next(), c.args
+	case "lt":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return field + " < " + next(), c.args
+	case "lte":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		return field + " <= " + next(), c.args
+	case "between":
+		if len(c.args) < 2 {
+			return constFalse, nil
+		}
+		return field + " BETWEEN " + next() + " AND " + next(), c.args
+	case "isnull":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		if null, _ := c.args[0].(bool); null {
+			return field + " IS NULL", nil
+		}
+		return field + " IS NOT NULL", nil
+	case "in":
+		if len(c.args) < 1 {
+			return constFalse, nil
+		}
+		values := toInterfaceSlice(c.args[0])
+		if len(values) == 0 {
+			// an empty IN-set matches nothing; "field IN ()" is invalid SQL.
+			return constFalse, nil
+		}
+		placeholders := make([]string, 0, len(values))
+		for range values {
+			placeholders = append(placeholders, next())
+		}
+		return field + " IN (" + strings.Join(placeholders, ",") + ")", values
+	}
+	return field + " = " + next(), c.args
+}
+
+// toInterfaceSlice unwraps a slice argument (e.g. []string, []int) passed to
+// the "in" lookup into a []interface{} suitable for query args.
+func toInterfaceSlice(v interface{}) []interface{} {
+	if vs, ok := v.([]interface{}); ok {
+		return vs
+	}
+	rv := reflect.ValueOf(v)
+	out := make([]interface{}, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package dal
+
+import "testing"
+
+func Test_splitLookup(t *testing.T) {
+	cases := []struct {
+		lookup    string
+		wantField string
+		wantOp    string
+	}{
+		{"age__gte", "age", "gte"},
+		{"name", "name", "exact"},
+		{"name__icontains", "name", "icontains"},
+		{"status__in", "status", "in"},
+	}
+	for _, c := range cases {
+		field, op := splitLookup(c.lookup)
+		if field != c.wantField || op != c.wantOp {
+			t.Errorf("splitLookup(%q) = (%q,%q), want (%q,%q)", c.lookup, field, op, c.wantField, c.wantOp)
+		}
+	}
+}
+
+func Test_Cond_toSQL(t *testing.T) {
+	argIndex := 1
+	sql, args := Q("age__gte", 18).toSQL(mysqlDialect{}, &argIndex)
+	if sql != "age >= ?" || len(args) != 1 || args[0] != 18 {
+		t.Errorf("got %q %+v", sql, args)
+	}
+
+	argIndex = 1
+	cond := Q("age__gte", 18).And(Q("name__icontains", "bob")).And(Q("status__in", []string{"banned"}).Not())
+	sql, args = cond.toSQL(postgresDialect{}, &argIndex)
+	wantSQL := "((age >= $1 AND name ILIKE $2) AND NOT (status IN ($3)))"
+	if sql != wantSQL {
+		t.Errorf("got %q, want %q", sql, wantSQL)
+	}
+	if len(args) != 3 || args[0] != 18 || args[1] != "%bob%" || args[2] != "banned" {
+		t.Errorf("got args %+v", args)
+	}
+}
+
+func Test_Cond_isnull(t *testing.T) {
+	argIndex := 1
+	sql, args := Q("deleted_at__isnull", true).toSQL(mysqlDialect{}, &argIndex)
+	if sql != "deleted_at IS NULL" || len(args) != 0 {
+		t.Errorf("got %q %+v", sql, args)
+	}
+}
+
+func Test_Cond_contains_caseSensitive(t *testing.T) {
+	argIndex := 1
+	sql, args := Q("name__contains", "Bob").toSQL(mysqlDialect{}, &argIndex)
+	if sql != "name LIKE BINARY ?" || len(args) != 1 || args[0] != "%Bob%" {
+		t.Errorf("got %q %+v", sql, args)
+	}
+}
+
+func Test_Cond_in_empty(t *testing.T) {
+	argIndex := 1
+	sql, args := Q("status__in", []string{}).toSQL(mysqlDialect{}, &argIndex)
+	if sql != constFalse || len(args) != 0 {
+		t.Errorf("got %q %+v, want %q with no args", sql, args, constFalse)
+	}
+}
+
+func Test_Cond_missingArg(t *testing.T) {
+	argIndex := 1
+	sql, args := Q("name__contains").toSQL(mysqlDialect{}, &argIndex)
+	if sql != constFalse || len(args) != 0 {
+		t.Errorf("got %q %+v, want %q with no args", sql, args, constFalse)
+	}
+}
@@ -2,15 +2,15 @@
 package dal
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
 	"strings"
-
-	_ "github.com/go-sql-driver/mysql"
+	"sync"
+	"time"
 )
 
-// TODO: Model.Read() is not parallel-able
 // Field `Records` contains the latest query results.
 // Default batch size is 4K, if record-size is 4K, the max_allowed_package 16M is reached.
 type Model struct {
@@ -18,17 +18,38 @@ type Model struct {
 	DataSourceName string
 	BatchSize      int
 	Records        []interface{}
-	rows           [][]interface{}
+	dialect        Dialect
+
+	// Logger, if set, receives one record per instrumented SQL round-trip
+	// (see Logger for which calls are covered).
+	Logger Logger
+	// Tracer, if set, opens a span around each instrumented Exec/Query/Prepare call.
+	Tracer Tracer
+	// Metrics, if set, receives per-query counters and latency histograms.
+	Metrics Metrics
+	// SlowQueryThreshold, if positive, escalates a round-trip's log entry
+	// to Logger.LogSlowQuery (when Logger implements SlowQueryLogger) once
+	// its duration reaches this threshold.
+	SlowQueryThreshold time.Duration
 }
 
 // `connections` maintains only one database access object for every Drivername+DataSourceName
-var connections map[string]*sql.DB = make(map[string]*sql.DB)
+var (
+	connMu      sync.RWMutex
+	connections = make(map[string]*sql.DB)
+)
+
+// connKey identifies the shared *sql.DB for this Model's driver+datasource.
+func (model Model) connKey() string {
+	return model.DriverName + model.DataSourceName
+}
 
 func (model *Model) getConn() (conn *sql.DB, err error) {
-	driverName := "mysql"
-	if model.DriverName != driverName {
-		return conn, fmt.Errorf(`model.driver name "%s" is not "%s"`, model.DriverName, driverName)
+	dialect, err := dialectFor(model.DriverName)
+	if err != nil {
+		return conn, fmt.Errorf("%v\n model.getConn failed to resolve dialect", err)
 	}
+	model.dialect = dialect
 	if model.DataSourceName == "" {
 		return conn, fmt.Errorf("model.datasource is empty")
 	}
@@ -36,13 +57,21 @@ func (model *Model) getConn() (conn *sql.DB, err error) {
 	if model.BatchSize == 0 {
 		model.BatchSize = 1 << 12 // 4k
 	} else if model.BatchSize < 0 {
-		return conn, fmt.Errorf("model.Batchsize cannot be negative")
+		return conn, fmt.Errorf("model.BatchSize cannot be negative")
 	}
 
-	key := model.DriverName + model.DataSourceName
+	key := model.connKey()
+	connMu.RLock()
 	conn, ok := connections[key]
+	connMu.RUnlock()
 	if ok {
-		return
+		return conn, nil
+	}
+
+	connMu.Lock()
+	defer connMu.Unlock()
+	if conn, ok = connections[key]; ok {
+		return conn, nil
 	}
 
 	conn, err = sql.Open(model.DriverName, model.DataSourceName)
@@ -53,15 +82,56 @@ func (model *Model) getConn() (conn *sql.DB, err error) {
 	return
 }
 
+// SetMaxOpenConns sets the maximum number of open connections to the
+// database; see (*sql.DB).SetMaxOpenConns.
+func (model Model) SetMaxOpenConns(n int) error {
+	conn, err := model.getConn()
+	if err != nil {
+		return fmt.Errorf("%v\n dal.SetMaxOpenConns failed on model.getConn", err)
+	}
+	conn.SetMaxOpenConns(n)
+	return nil
+}
+
+// SetMaxIdleConns sets the maximum number of idle connections kept in the
+// pool; see (*sql.DB).SetMaxIdleConns.
+func (model Model) SetMaxIdleConns(n int) error {
+	conn, err := model.getConn()
+	if err != nil {
+		return fmt.Errorf("%v\n dal.SetMaxIdleConns failed on model.getConn", err)
+	}
+	conn.SetMaxIdleConns(n)
+	return nil
+}
+
+// SetConnMaxLifetime sets the maximum amount of time a connection may be
+// reused; see (*sql.DB).SetConnMaxLifetime.
+func (model Model) SetConnMaxLifetime(d time.Duration) error {
+	conn, err := model.getConn()
+	if err != nil {
+		return fmt.Errorf("%v\n dal.SetConnMaxLifetime failed on model.getConn", err)
+	}
+	conn.SetConnMaxLifetime(d)
+	return nil
+}
+
 func (model Model) SQL(query string) (err error) {
 	conn, err := model.getConn()
 	if err != nil {
 		return fmt.Errorf("%v\n dal.SQL failed on model.getConn", err)
 	}
-	if _, err = conn.Exec(query); err != nil {
+	_, err = model.instrument(context.Background(), "Exec", "", query, nil, func(context.Context) (int64, error) {
+		res, err := conn.Exec(query)
+		if err != nil {
+			return 0, err
+		}
+		rows, _ := res.RowsAffected()
+		return rows, nil
+	})
+	if err != nil {
 		return fmt.Errorf("%v\n dal.SQL failed on conn.Exec", err)
 	}
-	return
+	return nil
 }
 
 // Create does insert-ignore on the given table.
@@ -90,34 +160,64 @@ func (model Model) write(table string, values interface{}, mode string) (rowsAff
 		return rowsAffected, fmt.Errorf("dal.%s: `values` has NO elements", mode)
 	}
 
-	fields, querief, placeholder := parseValue(rows.Index(0), table, mode)
-	step := model.Batchsize
-	valuesLimit := 1<<16 - 1 // limit of placeholders in mysql: 65,535
-	if size := valuesLimit / len(fields); size < step {
+	fields, tags := parseValue(rows.Index(0))
+	keys := parseKeys(rows.Index(0))
+	step := model.BatchSize
+	if size := model.dialect.PlaceholderLimit() / len(fields); size < step {
 		step = size
 	}
+	connKey := model.connKey()
 	tx, _ := conn.Begin()
 	for i := 0; i < rows.Len(); i += step {
-		placeholders := make([]string, 0, step)
-		var params []interface{}
-		for j := i; j < i+step && j < rows.Len(); j++ {
-			placeholders = append(placeholders, placeholder)
+		n := step
+		if i+n > rows.Len() {
+			n = rows.Len() - i
+		}
+		placeholders := make([]string, 0, n)
+		params := make([]interface{}, 0, n*len(fields))
+		argIndex := 1
+		for j := i; j < i+n; j++ {
 			row := rows.Index(j)
+			group := make([]string, 0, len(fields))
 			for u := 0; u < len(fields); u++ {
-				// params = append(params, row.FieldByName(fields[u]))
-				params = append(params, fmt.Sprintf("%v", row.FieldByName(fields[u])))
+				group = append(group, model.dialect.Placeholder(argIndex))
+				params = append(params, row.FieldByName(fields[u]).Interface())
+				argIndex++
 			}
+			placeholders = append(placeholders, "("+strings.Join(group, ",")+")")
 		}
 
-		query := fmt.Sprintf(querief, strings.Join(placeholders, ","))
-		stmt, err := tx.Prepare(query)
-		if err != nil {
-			return rowsAffected, fmt.Errorf(
-				"%v\n dal.%s failed on transaction.Prepare of %s",
-				err, mode, fmt.Sprintf(querief, placeholder+",..."))
+		key := stmtKey{connKey: connKey, table: table, mode: mode, batchSize: n, columns: strings.Join(tags, ",")}
+		entry, ok := globalStmtCache.acquire(key)
+		if !ok {
+			var query string
+			switch mode {
+			case "Create":
+				query = model.dialect.BuildInsertIgnore(table, tags, placeholders, keys)
+			case "Update":
+				query = model.dialect.BuildUpsert(table, tags, placeholders, keys)
+			}
+			var pstmt *sql.Stmt
+			if _, prepErr := model.instrument(context.Background(), "Prepare", table, query, nil, func(context.Context) (int64, error) {
+				var err error
+				pstmt, err = conn.Prepare(query)
+				return 0, err
+			}); prepErr != nil {
+				return rowsAffected, fmt.Errorf(
+					"%v\n dal.%s failed on conn.Prepare of %s", prepErr, mode, query)
+			}
+			entry = globalStmtCache.put(key, query, pstmt)
 		}
 
-		res, err := stmt.Exec(params...)
+		affected, err := model.instrument(context.Background(), "Exec", table, entry.query, params, func(context.Context) (int64, error) {
+			res, err := tx.Stmt(entry.stmt).Exec(params...)
+			if err != nil {
+				return 0, err
+			}
+			affected, _ := res.RowsAffected()
+			return affected, nil
+		})
+		globalStmtCache.release(entry)
 		if err != nil {
 			dispLen, trailing := len(fields), ""
 			if len(params) > dispLen {
@@ -126,10 +226,9 @@ func (model Model) write(table string, values interface{}, mode string) (rowsAff
 				dispLen = len(params)
 			}
 			return rowsAffected, fmt.Errorf(
-				"%v\n model.%s failed to write a record to table %s, query: %v\n values: %v%s",
-				err, mode, table, fmt.Sprintf(querief, placeholder+",..."), params[:dispLen], trailing)
+				"%v\n model.%s failed to write a record to table %s, values: %v%s",
+				err, mode, table, params[:dispLen], trailing)
 		}
-		affected, _ := res.RowsAffected()
 		rowsAffected += affected
 	}
 	if err = tx.Commit(); err != nil {
@@ -140,8 +239,10 @@ func (model Model) write(table string, values interface{}, mode string) (rowsAff
 	return
 }
 
-func parseValue(rv reflect.Value, table, mode string) (fields []string, query, placeholder string) {
-	var tags []string
+// parseValue walks rv's fields (descending into embedded structs) and
+// returns the Go field names alongside their `field` tag, defaulting the
+// tag to the field name when absent.
+func parseValue(rv reflect.Value) (fields, tags []string) {
 	var parse func(v reflect.Value)
 	parse = func(v reflect.Value) {
 		numField := v.NumField()
@@ -158,104 +259,114 @@ func parseValue(rv reflect.Value, table, mode string) (fields []string, query, p
 		}
 	}
 	parse(rv)
+	return
+}
 
-	placeholders, updates := make([]string, 0, len(tags)), make([]string, 0, len(tags))
-	for _, tag := range tags {
-		placeholders = append(placeholders, "?")
-		updates = append(updates, fmt.Sprintf("%s=values(%s)", tag, tag))
-	}
-	placeholder = "(" + strings.Join(placeholders, ",") + ")"
-	switch mode {
-	case "Create": // insert|ignore
-		query = fmt.Sprintf(`insert ignore into %s(%s) values %%s;`,
-			table,
-			strings.Join(tags, ","),
-		)
-	case "Update": // insert|update
-		query = fmt.Sprintf(`insert into %s(%s) values %%s on duplicate key update %s;`,
-			table,
-			strings.Join(tags, ","),
-			strings.Join(updates, ","),
-		)
+// parseKeys walks rv's fields (descending into embedded structs, like
+// parseValue) and returns the `field` tag of every field tagged
+// `pk:"true"`, so write() can tell BuildInsertIgnore/BuildUpsert which
+// column(s) actually identify a row instead of those dialects guessing by
+// field order.
+func parseKeys(rv reflect.Value) (keys []string) {
+	var parse func(v reflect.Value)
+	parse = func(v reflect.Value) {
+		numField := v.NumField()
+		for u := 0; u < numField; u++ {
+			if v.Field(u).Kind() == reflect.Struct {
+				parse(v.Field(u))
+				continue
+			}
+			if v.Type().Field(u).Tag.Get("pk") != "true" {
+				continue
+			}
+			field, tag := v.Type().Field(u).Name, v.Type().Field(u).Tag.Get("field")
+			if tag == "" {
+				tag = field
+			}
+			keys = append(keys, tag)
+		}
 	}
+	parse(rv)
 	return
 }
 
+// Read materializes every row matching the query into model.Records. It is
+// a thin wrapper over Iterate; callers processing large tables should use
+// Iterate or Stream instead to avoid loading the whole result set.
 func (model *Model) Read(table string, fields []string, condition string, readType interface{}) (err error) {
-	conn, err := model.getConn()
+	it, err := model.Iterate(context.Background(), table, fields, condition, readType)
 	if err != nil {
-		return fmt.Errorf("%v\n dal.Read failed on model.Init", err)
-	}
-
-	// query
-	query := fmt.Sprintf("select %s from %s %s", strings.Join(fields, ","), table, condition)
-	var rows *sql.Rows
-	if rows, err = conn.Query(query); err != nil {
-		return fmt.Errorf("%v\n dal.Read failed on conn.Query", err)
+		return fmt.Errorf("%v\n dal.Read failed on model.Iterate", err)
 	}
-	defer rows.Close()
+	defer it.Close()
 
-	// scan and set rows
-	model.rows = [][]interface{}{}
 	model.Records = []interface{}{}
-
 	tp := reflect.TypeOf(readType)
-	numField := tp.NumField()
-	for rows.Next() {
-		values := make([]interface{}, numField)
-		for i := 0; i < numField; i++ {
-			values[i] = reflect.New(reflect.PtrTo(tp.Field(i).Type)).Interface()
-		}
-		if err := rows.Scan(values...); err != nil {
-			return fmt.Errorf("%v\n model.Scan failed", err)
-		}
-		model.rows = append(model.rows, values)
-
+	for it.Next() {
 		elem := reflect.New(tp)
-		for i := 0; i < numField; i++ {
-			elem.Elem().FieldByName(tp.Field(i).Name).Set(reflect.ValueOf(values[i]).Elem().Elem())
+		if err := it.Scan(elem.Interface()); err != nil {
+			return fmt.Errorf("%v\n dal.Read failed on Iterator.Scan", err)
 		}
 		model.Records = append(model.Records, elem.Elem().Interface())
 	}
-	return nil
+	return it.Err()
 }
 
 func (model Model) Cleanup(table, fieldTime string, tm int64) (err error) {
 	conn, err := model.getConn()
 	if err != nil {
-		panic(fmt.Errorf("%v\n dal.DBInfo failed on model.init", err))
+		return fmt.Errorf("%v\n dal.Cleanup failed on model.getConn", err)
 	}
 
-	query, err := conn.Prepare(fmt.Sprintf("delete from %s where %s < ?;", table, fieldTime))
-	if err != nil {
+	queryStr := fmt.Sprintf("delete from %s where %s < %s;", table, fieldTime, model.dialect.Placeholder(1))
+	var stmt *sql.Stmt
+	if _, err = model.instrument(context.Background(), "Prepare", table, queryStr, nil, func(context.Context) (int64, error) {
+		var prepErr error
+		stmt, prepErr = conn.Prepare(queryStr)
+		return 0, prepErr
+	}); err != nil {
 		return fmt.Errorf("%v\n dal.Cleanup failed on conn.Prepare", err)
 	}
-	res, err := query.Exec(tm)
+
+	_, err = model.instrument(context.Background(), "Exec", table, queryStr, []interface{}{tm}, func(context.Context) (int64, error) {
+		res, err := stmt.Exec(tm)
+		if err != nil {
+			return 0, err
+		}
+		rowsAffected, _ := res.RowsAffected()
+		return rowsAffected, nil
+	})
 	if err != nil {
 		return fmt.Errorf("%v\n failed to cleanup outdated records in table %s", err, table)
 	}
-	rowsAffected, _ := res.RowsAffected()
-	fmt.Printf("dal.Cleanup: cleanup %d records from table %s", rowsAffected, table)
-	return
+	return nil
 }
 
-func (model Model) DBInfo() (info []string) {
+// DBInfo returns the backend's version string(s); an error is returned
+// (never a panic) if the connection or query fails.
+func (model Model) DBInfo() (info []string, err error) {
 	conn, err := model.getConn()
 	if err != nil {
-		panic(fmt.Errorf("%v\n dal.DBInfo failed on model.init", err))
+		return nil, fmt.Errorf("%v\n dal.DBInfo failed on model.getConn", err)
 	}
 
-	rows, err := conn.Query("select version();")
-	if err != nil {
-		panic(err)
-		return
+	queryStr := model.dialect.VersionQuery()
+	var rows *sql.Rows
+	if _, err = model.instrument(context.Background(), "Query", "", queryStr, nil, func(context.Context) (int64, error) {
+		var queryErr error
+		rows, queryErr = conn.Query(queryStr)
+		return 0, queryErr
+	}); err != nil {
+		return nil, fmt.Errorf("%v\n dal.DBInfo failed on conn.Query", err)
 	}
+	defer rows.Close()
+
 	for rows.Next() {
 		var ver string
 		if err := rows.Scan(&ver); err != nil {
-			panic(fmt.Errorf("cannot get database version info, error: %v", err))
+			return nil, fmt.Errorf("%v\n dal.DBInfo failed on rows.Scan", err)
 		}
 		info = append(info, "system db version: "+ver)
 	}
-	return info
+	return info, rows.Err()
 }
@@ -1,7 +1,7 @@
 package dal
 
 import (
-	"fmt"
+	"context"
 	"reflect"
 	"testing"
 )
@@ -45,7 +45,8 @@ func Test_write(t *testing.T) {
 	}
 
 	// version
-	if info := model.DBInfo(); len(info) != 1 {
+	info, err := model.DBInfo()
+	if err != nil || len(info) != 1 {
 		t.Error("cannot get database version info")
 		return
 	}
@@ -75,6 +76,46 @@ func Test_write(t *testing.T) {
 
 	// re-read
 	checkRead()
+
+	// iterate
+	it, err := model.Iterate(context.Background(), "user", []string{"id", "name"}, "", T{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var iterated []T
+	for it.Next() {
+		var row T
+		if err := it.Scan(&row); err != nil {
+			t.Error(err)
+		}
+		iterated = append(iterated, row)
+	}
+	if err := it.Err(); err != nil {
+		t.Error(err)
+	}
+	it.Close()
+	if len(iterated) != len(values) {
+		t.Errorf("length of iterated results (%d) != length of records (%d)", len(iterated), len(values))
+	}
+
+	// stream
+	out := make(chan interface{})
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- model.Stream(context.Background(), "user", []string{"id", "name"}, "", T{}, out)
+		close(out)
+	}()
+	var streamed []T
+	for v := range out {
+		streamed = append(streamed, v.(T))
+	}
+	if err := <-streamErr; err != nil {
+		t.Error(err)
+	}
+	if len(streamed) != len(values) {
+		t.Errorf("length of streamed results (%d) != length of records (%d)", len(streamed), len(values))
+	}
 }
 
 func Test_parseValue(t *testing.T) {
@@ -91,15 +132,14 @@ func Test_parseValue(t *testing.T) {
 		},
 		Age: 12,
 	}
-	fields, query, placeholder := parseValue(reflect.ValueOf(p), "staff", "Update")
-	query = fmt.Sprintf(query, placeholder)
+	fields, tags := parseValue(reflect.ValueOf(p))
 
 	tFields := []string{"Name", "Age"}
 	if len(fields) != len(tFields) || fields[0] != tFields[0] || fields[1] != tFields[1] {
 		t.Errorf("output fields %+v is different from %+v", fields, tFields)
 	}
-	tQuery := "insert into staff(name,age) values (?,?) on duplicate key update name=values(name),age=values(age);"
-	if query != tQuery {
-		t.Error("output query string is wrong")
+	tTags := []string{"name", "age"}
+	if len(tags) != len(tTags) || tags[0] != tTags[0] || tags[1] != tTags[1] {
+		t.Errorf("output tags %+v is different from %+v", tags, tTags)
 	}
 }
@@ -0,0 +1,111 @@
+package dal
+
+import "fmt"
+
+// Dialect abstracts the SQL syntax differences between database backends so
+// Model does not need to special-case each driver.
+type Dialect interface {
+	// Name is the dialect's canonical name, matching the DriverName it serves.
+	Name() string
+	// Placeholder returns the parameter placeholder for the argIndex'th
+	// (1-based) argument of a statement.
+	Placeholder(argIndex int) string
+	// QuoteIdent quotes a table/column identifier.
+	QuoteIdent(name string) string
+	// VersionQuery returns the query used by Model.DBInfo to fetch the
+	// backend's version string.
+	VersionQuery() string
+	// PlaceholderLimit is the maximum number of bound parameters the
+	// backend accepts in a single statement.
+	PlaceholderLimit() int
+	// LikeOperator is the SQL operator used for (case-insensitive) pattern
+	// matching, e.g. "LIKE" or, on Postgres, "ILIKE".
+	LikeOperator() string
+	// CaseSensitiveLikeOperator is the SQL operator used for case-sensitive
+	// pattern matching, e.g. "LIKE" or, on MySQL (whose default collation
+	// is case-insensitive), "LIKE BINARY".
+	CaseSensitiveLikeOperator() string
+	// BuildLimitOffset renders the trailing clause that bounds a result
+	// set. offset <= 0 means no rows are skipped; limit < 0 means no cap.
+	// A dialect may still render an explicit zero offset or maximal limit
+	// where its syntax requires one (MSSQL, MySQL).
+	BuildLimitOffset(limit, offset int) string
+	// BuildInsertIgnore builds an insert that silently skips rows that
+	// violate a uniqueness constraint. placeholders holds one already
+	// parenthesized group, e.g. "(?,?)", per row. keys names the columns
+	// identifying the row, for dialects (MSSQL) whose insert-ignore syntax
+	// requires an explicit match target; dialects whose syntax does not
+	// (MySQL/Postgres/SQLite) ignore it.
+	BuildInsertIgnore(table string, cols, placeholders, keys []string) string
+	// BuildUpsert builds an insert that updates the existing row on a
+	// uniqueness conflict. keys names the columns identifying that row;
+	// callers should supply the struct's real primary/unique key column(s)
+	// (e.g. via the `pk` tag) rather than leaving it empty, since a
+	// dialect that requires a conflict target falls back to guessing the
+	// first column otherwise.
+	BuildUpsert(table string, cols, placeholders, keys []string) string
+
+	// CreateTableSQL renders a CREATE TABLE that is a no-op if table
+	// already exists.
+	CreateTableSQL(table string, cols []ColumnDef) string
+	// AddColumnSQL renders an ALTER TABLE adding a single column.
+	AddColumnSQL(table string, col ColumnDef) string
+	// CreateIndexSQL renders a CREATE [UNIQUE] INDEX over cols.
+	CreateIndexSQL(table, indexName string, cols []string, unique bool) string
+	// ExistingColumnsQuery returns a query (and its args) yielding one
+	// (column_name, data_type) row per existing column of table; an empty
+	// result means the table does not exist.
+	ExistingColumnsQuery(table string) (query string, args []interface{})
+	// ExistingIndexesQuery returns a query (and its args) yielding one
+	// index_name row per index already defined on table, so Sync can skip
+	// re-issuing a CREATE INDEX that would otherwise fail on a dialect
+	// without "IF NOT EXISTS" support for indexes (e.g. MySQL, MSSQL).
+	ExistingIndexesQuery(table string) (query string, args []interface{})
+}
+
+// ColumnDef describes one struct-tag-derived column for Model.Sync.
+type ColumnDef struct {
+	Name       string // the `field` tag / column name
+	Type       string // SQL type, from the `type`(+`size`) tag or inferred
+	PK         bool
+	Null       bool
+	Default    string
+	HasDefault bool
+	Index      bool
+	Unique     bool
+	FK         string // "table(column)", from the `fk` tag
+}
+
+// renderColumnDef renders the common "name TYPE [constraints]" fragment
+// shared by every dialect's CREATE TABLE / ALTER TABLE ADD COLUMN.
+func renderColumnDef(d Dialect, col ColumnDef) string {
+	def := d.QuoteIdent(col.Name) + " " + col.Type
+	if col.PK {
+		def += " PRIMARY KEY"
+	}
+	if !col.Null {
+		def += " NOT NULL"
+	}
+	if col.HasDefault {
+		def += " DEFAULT " + col.Default
+	}
+	if col.FK != "" {
+		def += " REFERENCES " + col.FK
+	}
+	return def
+}
+
+// dialects maps a Model.DriverName to its Dialect implementation.
+var dialects = make(map[string]Dialect)
+
+func registerDialect(d Dialect) {
+	dialects[d.Name()] = d
+}
+
+func dialectFor(driverName string) (Dialect, error) {
+	d, ok := dialects[driverName]
+	if !ok {
+		return nil, fmt.Errorf(`dal: no dialect registered for driver "%s"`, driverName)
+	}
+	return d, nil
+}
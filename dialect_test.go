@@ -0,0 +1,56 @@
+package dal
+
+import "testing"
+
+func Test_dialectFor(t *testing.T) {
+	for _, name := range []string{"mysql", "postgres", "sqlite3", "sqlserver"} {
+		if _, err := dialectFor(name); err != nil {
+			t.Errorf("dialectFor(%q) failed: %v", name, err)
+		}
+	}
+	if _, err := dialectFor("oracle"); err == nil {
+		t.Error("dialectFor(\"oracle\") should fail: no such dialect registered")
+	}
+}
+
+func Test_BuildInsertIgnore(t *testing.T) {
+	cols := []string{"id", "name"}
+	placeholders := []string{"(?,?)"}
+
+	tQuery := "insert ignore into user(id,name) values (?,?);"
+	if query := (mysqlDialect{}).BuildInsertIgnore("user", cols, placeholders, nil); query != tQuery {
+		t.Errorf("mysql: got %q, want %q", query, tQuery)
+	}
+
+	tQuery = "insert or ignore into user(id,name) values (?,?);"
+	if query := (sqliteDialect{}).BuildInsertIgnore("user", cols, placeholders, nil); query != tQuery {
+		t.Errorf("sqlite3: got %q, want %q", query, tQuery)
+	}
+
+	placeholders = []string{"($1,$2)"}
+	tQuery = "insert into user(id,name) values ($1,$2) on conflict do nothing;"
+	if query := (postgresDialect{}).BuildInsertIgnore("user", cols, placeholders, nil); query != tQuery {
+		t.Errorf("postgres: got %q, want %q", query, tQuery)
+	}
+
+	tQuery = "merge into user as target using (values (@p1,@p2)) as source(id,name) on target.id=source.id when not matched then insert (id,name) values (source.id,source.name);"
+	if query := (mssqlDialect{}).BuildInsertIgnore("user", cols, []string{"(@p1,@p2)"}, []string{"id"}); query != tQuery {
+		t.Errorf("sqlserver: got %q, want %q", query, tQuery)
+	}
+}
+
+func Test_BuildUpsert(t *testing.T) {
+	cols := []string{"id", "name"}
+	placeholders := []string{"(?,?)"}
+
+	tQuery := "insert into user(id,name) values (?,?) on duplicate key update id=values(id),name=values(name);"
+	if query := (mysqlDialect{}).BuildUpsert("user", cols, placeholders, nil); query != tQuery {
+		t.Errorf("mysql: got %q, want %q", query, tQuery)
+	}
+
+	placeholders = []string{"($1,$2)"}
+	tQuery = "insert into user(id,name) values ($1,$2) on conflict (id) do update set id=excluded.id,name=excluded.name;"
+	if query := (postgresDialect{}).BuildUpsert("user", cols, placeholders, nil); query != tQuery {
+		t.Errorf("postgres: got %q, want %q", query, tQuery)
+	}
+}
@@ -0,0 +1,102 @@
+package dal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Iterator streams a Model.Iterate result row by row instead of materializing
+// the whole set, so callers can process arbitrarily large tables and bail
+// out early via ctx.
+type Iterator struct {
+	rows *sql.Rows
+	tp   reflect.Type
+	plan *decodePlan
+}
+
+// Iterate runs the query and returns an Iterator over the results; fields
+// names the selected columns and readType the struct they are scanned into,
+// one column per struct field in field order, as in Model.Read. The
+// returned Iterator must be closed by the caller.
+func (model *Model) Iterate(ctx context.Context, table string, fields []string, condition string, readType interface{}) (*Iterator, error) {
+	conn, err := model.getConn()
+	if err != nil {
+		return nil, fmt.Errorf("%v\n dal.Iterate failed on model.getConn", err)
+	}
+
+	query := fmt.Sprintf("select %s from %s %s", strings.Join(fields, ","), table, condition)
+	var rows *sql.Rows
+	if _, err = model.instrument(ctx, "Query", table, query, nil, func(ctx context.Context) (int64, error) {
+		var queryErr error
+		rows, queryErr = conn.QueryContext(ctx, query)
+		return 0, queryErr
+	}); err != nil {
+		return nil, fmt.Errorf("%v\n dal.Iterate failed on conn.QueryContext", err)
+	}
+	tp := reflect.TypeOf(readType)
+	return &Iterator{rows: rows, tp: tp, plan: planFor(tp)}, nil
+}
+
+// Next advances the Iterator to the next row, returning false when the
+// result set is exhausted or an error occurred (check Err to tell which).
+func (it *Iterator) Next() bool {
+	return it.rows.Next()
+}
+
+// Scan decodes the current row into dst, a pointer to a value of the
+// readType passed to Iterate. It addresses dst's fields directly via the
+// same cached decodePlan ScanAll/ScanOne use, instead of allocating and
+// copying through a reflect.New(reflect.PtrTo(...)) per field per row.
+func (it *Iterator) Scan(dst interface{}) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr {
+		return fmt.Errorf("dal.Iterator.Scan: dst must be a pointer")
+	}
+
+	elem := dv.Elem()
+	dest := make([]interface{}, len(it.plan.index))
+	for i, path := range it.plan.index {
+		dest[i] = elem.FieldByIndex(path).Addr().Interface()
+	}
+	if err := it.rows.Scan(dest...); err != nil {
+		return fmt.Errorf("%v\n dal.Iterator.Scan failed on rows.Scan", err)
+	}
+	return nil
+}
+
+// Err returns the first error encountered while iterating, if any.
+func (it *Iterator) Err() error {
+	return it.rows.Err()
+}
+
+// Close releases the underlying *sql.Rows; safe to call multiple times.
+func (it *Iterator) Close() error {
+	return it.rows.Close()
+}
+
+// Stream iterates the query and pushes decoded readType values onto out,
+// honoring ctx cancellation on both the query and the send.
+func (model *Model) Stream(ctx context.Context, table string, fields []string, condition string, readType interface{}, out chan<- interface{}) error {
+	it, err := model.Iterate(ctx, table, fields, condition, readType)
+	if err != nil {
+		return fmt.Errorf("%v\n dal.Stream failed on model.Iterate", err)
+	}
+	defer it.Close()
+
+	tp := reflect.TypeOf(readType)
+	for it.Next() {
+		elem := reflect.New(tp)
+		if err := it.Scan(elem.Interface()); err != nil {
+			return fmt.Errorf("%v\n dal.Stream failed on Iterator.Scan", err)
+		}
+		select {
+		case out <- elem.Elem().Interface():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return it.Err()
+}
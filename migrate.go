@@ -0,0 +1,347 @@
+package dal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Sync reconciles the live schema with models, issuing CREATE TABLE IF NOT
+// EXISTS for tables that don't exist yet and additive ALTER TABLE ADD
+// COLUMN / CREATE INDEX for ones that do. It never alters or drops an
+// existing column; a type mismatch is logged, not executed. Each model must
+// either implement Tabler or have its table name derived from its type name.
+func (model *Model) Sync(ctx context.Context, models ...interface{}) error {
+	conn, err := model.getConn()
+	if err != nil {
+		return fmt.Errorf("%v\n dal.Sync failed on model.getConn", err)
+	}
+	ddl, err := model.planSync(ctx, conn, models...)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range ddl {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("%v\n dal.Sync failed executing %q", err, stmt)
+		}
+	}
+	return nil
+}
+
+// MustSync is like Sync but panics on error.
+func (model *Model) MustSync(ctx context.Context, models ...interface{}) {
+	if err := model.Sync(ctx, models...); err != nil {
+		panic(err)
+	}
+}
+
+// PlanSync is Sync's dry-run counterpart: it returns the DDL statements Sync
+// would execute without running them.
+func (model *Model) PlanSync(ctx context.Context, models ...interface{}) ([]string, error) {
+	conn, err := model.getConn()
+	if err != nil {
+		return nil, fmt.Errorf("%v\n dal.PlanSync failed on model.getConn", err)
+	}
+	return model.planSync(ctx, conn, models...)
+}
+
+func (model *Model) planSync(ctx context.Context, conn *sql.DB, models ...interface{}) (ddl []string, err error) {
+	for _, m := range models {
+		table := tableNameOf(m)
+		tp := reflect.TypeOf(m)
+		if tp.Kind() == reflect.Ptr {
+			tp = tp.Elem()
+		}
+		cols := columnDefsOf(tp)
+
+		existing, err := existingColumns(ctx, conn, model.dialect, table)
+		if err != nil {
+			return nil, fmt.Errorf("%v\n dal.Sync failed inspecting table %s", err, table)
+		}
+		existingIdx, err := existingIndexes(ctx, conn, model.dialect, table)
+		if err != nil {
+			return nil, fmt.Errorf("%v\n dal.Sync failed inspecting indexes on table %s", err, table)
+		}
+
+		if len(existing) == 0 {
+			ddl = append(ddl, model.dialect.CreateTableSQL(table, cols))
+			for _, col := range cols {
+				ddl = append(ddl, indexDDLFor(model.dialect, table, col, existingIdx)...)
+			}
+			continue
+		}
+
+		for _, col := range cols {
+			existingType, ok := existing[strings.ToLower(col.Name)]
+			if !ok {
+				ddl = append(ddl, model.dialect.AddColumnSQL(table, col))
+			} else if !typeCompatible(existingType, col.Type) {
+				log.Printf("dal.Sync: table %s column %s: existing type %q differs from struct type %q; refusing to alter",
+					table, col.Name, existingType, col.Type)
+			}
+			// An index/unique tag can be added to an already-existing
+			// column too, so this reconciliation isn't limited to the
+			// newly-added-column branch above.
+			ddl = append(ddl, indexDDLFor(model.dialect, table, col, existingIdx)...)
+		}
+	}
+	return ddl, nil
+}
+
+// indexDDLFor returns the CREATE INDEX/CREATE UNIQUE INDEX statements col's
+// Index/Unique tags imply but existingIdx (as returned by existingIndexes)
+// doesn't already have, following Sync's "idx_"/"uniq_" naming convention.
+func indexDDLFor(d Dialect, table string, col ColumnDef, existingIdx map[string]bool) (ddl []string) {
+	if col.Index {
+		name := "idx_" + table + "_" + col.Name
+		if !existingIdx[strings.ToLower(name)] {
+			ddl = append(ddl, d.CreateIndexSQL(table, name, []string{col.Name}, false))
+		}
+	}
+	if col.Unique {
+		name := "uniq_" + table + "_" + col.Name
+		if !existingIdx[strings.ToLower(name)] {
+			ddl = append(ddl, d.CreateIndexSQL(table, name, []string{col.Name}, true))
+		}
+	}
+	return ddl
+}
+
+// existingColumns returns a lowercased column-name -> data-type map for
+// table, empty if the table does not exist.
+func existingColumns(ctx context.Context, conn *sql.DB, d Dialect, table string) (map[string]string, error) {
+	query, args := d.ExistingColumnsQuery(table)
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[string]string)
+	for rows.Next() {
+		var name, dataType string
+		if err := rows.Scan(&name, &dataType); err != nil {
+			return nil, err
+		}
+		existing[strings.ToLower(name)] = dataType
+	}
+	return existing, rows.Err()
+}
+
+// existingIndexes returns a lowercased set of index names already defined
+// on table, empty if the table (or none of its indexes) exist yet.
+func existingIndexes(ctx context.Context, conn *sql.DB, d Dialect, table string) (map[string]bool, error) {
+	query, args := d.ExistingIndexesQuery(table)
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		existing[strings.ToLower(name)] = true
+	}
+	return existing, rows.Err()
+}
+
+// typeCompatible loosely compares a live column's reported type against the
+// struct's declared type, ignoring size/precision (e.g. "varchar(64)" and
+// "VARCHAR" are compatible).
+func typeCompatible(existing, want string) bool {
+	norm := func(s string) string {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if i := strings.Index(s, "("); i >= 0 {
+			s = s[:i]
+		}
+		return s
+	}
+	return norm(existing) == norm(want)
+}
+
+// Tabler lets a struct override the table name Sync derives for it; absent
+// that, the table name falls back to the snake_case of the type name.
+type Tabler interface {
+	TableName() string
+}
+
+func tableNameOf(m interface{}) string {
+	if t, ok := m.(Tabler); ok {
+		return t.TableName()
+	}
+	tp := reflect.TypeOf(m)
+	if tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+	return toSnakeCase(tp.Name())
+}
+
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToLower(b.String())
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// columnDefsOf walks tp's fields (descending into embedded structs, like
+// parseValue) and derives a ColumnDef from the `field`, `type`, `size`,
+// `pk`, `null`, `default`, `index`, `unique`, and `fk` tags.
+func columnDefsOf(tp reflect.Type) (cols []ColumnDef) {
+	var walk func(tp reflect.Type)
+	walk = func(tp reflect.Type) {
+		for i := 0; i < tp.NumField(); i++ {
+			f := tp.Field(i)
+			if f.Type.Kind() == reflect.Struct && f.Type != timeType {
+				walk(f.Type)
+				continue
+			}
+
+			name := f.Tag.Get("field")
+			if name == "" {
+				name = f.Name
+			}
+
+			sqlType := f.Tag.Get("type")
+			if sqlType == "" {
+				sqlType = inferSQLType(f.Type, f.Tag.Get("size"))
+			} else if size := f.Tag.Get("size"); size != "" {
+				sqlType = fmt.Sprintf("%s(%s)", sqlType, size)
+			}
+
+			def, hasDefault := f.Tag.Lookup("default")
+			cols = append(cols, ColumnDef{
+				Name:       name,
+				Type:       sqlType,
+				PK:         f.Tag.Get("pk") == "true",
+				Null:       f.Tag.Get("null") == "true",
+				Default:    def,
+				HasDefault: hasDefault,
+				Index:      f.Tag.Get("index") == "true",
+				Unique:     f.Tag.Get("unique") == "true",
+				FK:         f.Tag.Get("fk"),
+			})
+		}
+	}
+	walk(tp)
+	return
+}
+
+func inferSQLType(tp reflect.Type, size string) string {
+	switch {
+	case tp == timeType:
+		return "DATETIME"
+	case tp.Kind() == reflect.String:
+		if size != "" {
+			return fmt.Sprintf("VARCHAR(%s)", size)
+		}
+		return "TEXT"
+	case tp.Kind() == reflect.Bool:
+		return "BOOLEAN"
+	case tp.Kind() >= reflect.Int && tp.Kind() <= reflect.Uint64:
+		return "INTEGER"
+	case tp.Kind() == reflect.Float32 || tp.Kind() == reflect.Float64:
+		return "DOUBLE PRECISION"
+	default:
+		return "TEXT"
+	}
+}
+
+// migrationsTable tracks which registered migrations have run.
+const migrationsTable = "dal_migrations"
+
+type registeredMigration struct {
+	name string
+	up   func(*sql.Tx) error
+	down func(*sql.Tx) error
+}
+
+// migrations holds every migration registered via RegisterMigration, in
+// registration order.
+var migrations []registeredMigration
+
+// RegisterMigration registers an ordered, named migration; up runs inside a
+// transaction via Model.MigrateUp. down is stored but nothing in this
+// package runs it.
+func RegisterMigration(name string, up, down func(*sql.Tx) error) {
+	migrations = append(migrations, registeredMigration{name: name, up: up, down: down})
+}
+
+// MigrateUp runs every registered migration not yet recorded in the
+// migrations table, in registration order, each in its own transaction.
+func (model *Model) MigrateUp(ctx context.Context) error {
+	conn, err := model.getConn()
+	if err != nil {
+		return fmt.Errorf("%v\n dal.MigrateUp failed on model.getConn", err)
+	}
+
+	createTable := model.dialect.CreateTableSQL(migrationsTable, []ColumnDef{
+		{Name: "name", Type: "VARCHAR(255)", PK: true},
+		{Name: "applied_at", Type: "TEXT"},
+	})
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return fmt.Errorf("%v\n dal.MigrateUp failed creating %s", err, migrationsTable)
+	}
+
+	applied, err := existingMigrations(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("%v\n dal.MigrateUp failed listing applied migrations", err)
+	}
+
+	for _, m := range migrations {
+		if applied[m.name] {
+			continue
+		}
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("%v\n dal.MigrateUp failed on conn.BeginTx for %q", err, m.name)
+		}
+		if err := m.up(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%v\n dal.MigrateUp failed running migration %q", err, m.name)
+		}
+
+		insert := fmt.Sprintf("insert into %s(name,applied_at) values (%s,%s);",
+			migrationsTable, model.dialect.Placeholder(1), model.dialect.Placeholder(2))
+		if _, err := tx.ExecContext(ctx, insert, m.name, time.Now().UTC().Format(time.RFC3339)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%v\n dal.MigrateUp failed recording migration %q", err, m.name)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("%v\n dal.MigrateUp failed committing migration %q", err, m.name)
+		}
+	}
+	return nil
+}
+
+func existingMigrations(ctx context.Context, conn *sql.DB) (map[string]bool, error) {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("select name from %s;", migrationsTable))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		applied[name] = true
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,103 @@
+package dal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_toSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"User":     "user",
+		"UserInfo": "user_info",
+		"ID":       "i_d",
+	}
+	for in, want := range cases {
+		if got := toSnakeCase(in); got != want {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func Test_typeCompatible(t *testing.T) {
+	if !typeCompatible("varchar(64)", "VARCHAR(128)") {
+		t.Error("varchar(64) should be compatible with VARCHAR(128): size is ignored")
+	}
+	if typeCompatible("int", "VARCHAR(64)") {
+		t.Error("int should not be compatible with VARCHAR(64)")
+	}
+}
+
+func Test_columnDefsOf(t *testing.T) {
+	type User struct {
+		ID    int    `field:"id" pk:"true"`
+		Name  string `field:"name" type:"varchar" size:"64" index:"true"`
+		Email string `field:"email" unique:"true"`
+		Age   int    `field:"age" null:"true" default:"0"`
+	}
+	cols := columnDefsOf(reflect.TypeOf(User{}))
+	if len(cols) != 4 {
+		t.Fatalf("got %d columns, want 4", len(cols))
+	}
+	if !cols[0].PK || cols[0].Name != "id" {
+		t.Errorf("id column = %+v", cols[0])
+	}
+	if cols[1].Type != "varchar(64)" || !cols[1].Index {
+		t.Errorf("name column = %+v", cols[1])
+	}
+	if !cols[2].Unique {
+		t.Errorf("email column = %+v", cols[2])
+	}
+	if !cols[3].Null || !cols[3].HasDefault || cols[3].Default != "0" {
+		t.Errorf("age column = %+v", cols[3])
+	}
+}
+
+func Test_tableNameOf(t *testing.T) {
+	type UserInfo struct{}
+	if got := tableNameOf(UserInfo{}); got != "user_info" {
+		t.Errorf("tableNameOf(UserInfo{}) = %q, want %q", got, "user_info")
+	}
+}
+
+type namedTable struct{}
+
+func (namedTable) TableName() string { return "custom_table" }
+
+func Test_tableNameOf_Tabler(t *testing.T) {
+	if got := tableNameOf(namedTable{}); got != "custom_table" {
+		t.Errorf("tableNameOf(namedTable{}) = %q, want %q", got, "custom_table")
+	}
+}
+
+func Test_indexDDLFor(t *testing.T) {
+	col := ColumnDef{Name: "email", Index: true, Unique: true}
+
+	ddl := indexDDLFor(mysqlDialect{}, "user", col, map[string]bool{})
+	want := []string{
+		"create index idx_user_email on user (email);",
+		"create unique index uniq_user_email on user (email);",
+	}
+	if len(ddl) != len(want) || ddl[0] != want[0] || ddl[1] != want[1] {
+		t.Errorf("got %+v, want %+v", ddl, want)
+	}
+
+	// Already-present indexes (by name, case-insensitively) are skipped.
+	ddl = indexDDLFor(mysqlDialect{}, "user", col, map[string]bool{
+		"idx_user_email":  true,
+		"uniq_user_email": true,
+	})
+	if len(ddl) != 0 {
+		t.Errorf("got %+v, want no DDL for already-existing indexes", ddl)
+	}
+}
+
+func Test_CreateTableSQL(t *testing.T) {
+	cols := []ColumnDef{
+		{Name: "id", Type: "INTEGER", PK: true},
+		{Name: "name", Type: "VARCHAR(64)"},
+	}
+	want := "create table if not exists user (`id` INTEGER PRIMARY KEY NOT NULL, `name` VARCHAR(64) NOT NULL);"
+	if got := (mysqlDialect{}).CreateTableSQL("user", cols); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package dal
+
+import (
+	"fmt"
+	"strings"
+
+	_ "github.com/denisenkom/go-mssqldb"
+)
+
+// ---- MSSQL ----
+// Both insert-ignore and upsert are expressed as a MERGE, since T-SQL has
+// no INSERT ... ON CONFLICT equivalent.
+
+type mssqlDialect struct{}
+
+func init() { registerDialect(mssqlDialect{}) }
+
+func (mssqlDialect) Name() string { return "sqlserver" }
+
+func (mssqlDialect) Placeholder(argIndex int) string { return fmt.Sprintf("@p%d", argIndex) }
+
+func (mssqlDialect) QuoteIdent(name string) string { return "[" + name + "]" }
+
+func (mssqlDialect) VersionQuery() string { return "select @@version;" }
+
+func (mssqlDialect) PlaceholderLimit() int { return 2100 }
+
+func (mssqlDialect) LikeOperator() string { return "LIKE" }
+
+// CaseSensitiveLikeOperator: MSSQL's case sensitivity is a property of the
+// column/database collation, not the operator, so there is no drop-in
+// case-sensitive LIKE variant here; most default (CI) collations will still
+// match case-insensitively. Callers needing strict case sensitivity should
+// apply a COLLATE *_CS_AS clause to the column themselves.
+func (mssqlDialect) CaseSensitiveLikeOperator() string { return "LIKE" }
+
+// BuildLimitOffset renders the OFFSET ... FETCH NEXT clause. T-SQL requires
+// an ORDER BY to precede it; callers must supply one (e.g. via OrderBy).
+func (mssqlDialect) BuildLimitOffset(limit, offset int) string {
+	if limit < 0 && offset <= 0 {
+		return ""
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	clause := fmt.Sprintf(" offset %d rows", offset)
+	if limit >= 0 {
+		clause += fmt.Sprintf(" fetch next %d rows only", limit)
+	}
+	return clause
+}
+
+func (d mssqlDialect) CreateTableSQL(table string, cols []ColumnDef) string {
+	defs := make([]string, 0, len(cols))
+	for _, col := range cols {
+		defs = append(defs, renderColumnDef(d, col))
+	}
+	return fmt.Sprintf("if object_id('%s', 'U') is null create table %s (%s);", table, table, strings.Join(defs, ", "))
+}
+
+func (d mssqlDialect) AddColumnSQL(table string, col ColumnDef) string {
+	return fmt.Sprintf("alter table %s add %s;", table, renderColumnDef(d, col))
+}
+
+func (mssqlDialect) CreateIndexSQL(table, indexName string, cols []string, unique bool) string {
+	kind := "index"
+	if unique {
+		kind = "unique index"
+	}
+	return fmt.Sprintf("create %s %s on %s (%s);", kind, indexName, table, strings.Join(cols, ","))
+}
+
+func (mssqlDialect) ExistingColumnsQuery(table string) (string, []interface{}) {
+	return "select c.name, t.name as data_type from sys.columns c " +
+			"join sys.types t on c.user_type_id = t.user_type_id where c.object_id = OBJECT_ID(@p1)",
+		[]interface{}{table}
+}
+
+func (mssqlDialect) ExistingIndexesQuery(table string) (string, []interface{}) {
+	return "select name from sys.indexes where object_id = OBJECT_ID(@p1) and name is not null",
+		[]interface{}{table}
+}
+
+func (mssqlDialect) BuildInsertIgnore(table string, cols, placeholders, keys []string) string {
+	if len(keys) == 0 {
+		keys = cols[:1]
+	}
+	return buildMerge(table, cols, placeholders, keys, false)
+}
+
+func (mssqlDialect) BuildUpsert(table string, cols, placeholders, keys []string) string {
+	if len(keys) == 0 {
+		keys = cols[:1]
+	}
+	return buildMerge(table, cols, placeholders, keys, true)
+}
+
+// buildMerge renders a MERGE statement against a VALUES row source, matching
+// on keys. When update is false, matched rows are left untouched (insert
+// semantics); when true, matched rows are updated.
+func buildMerge(table string, cols, placeholders, keys []string, update bool) string {
+	on := make([]string, 0, len(keys))
+	for _, key := range keys {
+		on = append(on, fmt.Sprintf("target.%s=source.%s", key, key))
+	}
+	var matched string
+	if update {
+		sets := make([]string, 0, len(cols))
+		for _, col := range cols {
+			sets = append(sets, fmt.Sprintf("%s=source.%s", col, col))
+		}
+		matched = fmt.Sprintf("when matched then update set %s ", strings.Join(sets, ","))
+	}
+	return fmt.Sprintf(
+		`merge into %s as target using (values %s) as source(%s) on %s %swhen not matched then insert (%s) values (%s);`,
+		table,
+		strings.Join(placeholders, ","),
+		strings.Join(cols, ","),
+		strings.Join(on, " and "),
+		matched,
+		strings.Join(cols, ","),
+		strings.Join(colsAsSource(cols), ","),
+	)
+}
+
+func colsAsSource(cols []string) []string {
+	sourced := make([]string, 0, len(cols))
+	for _, col := range cols {
+		sourced = append(sourced, "source."+col)
+	}
+	return sourced
+}
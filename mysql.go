@@ -0,0 +1,96 @@
+package dal
+
+import (
+	"fmt"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// ---- MySQL ----
+// Model.Update can update a row on duplicated key.
+
+type mysqlDialect struct{}
+
+func init() { registerDialect(mysqlDialect{}) }
+
+func (mysqlDialect) Name() string { return "mysql" }
+
+func (mysqlDialect) Placeholder(int) string { return "?" }
+
+func (mysqlDialect) QuoteIdent(name string) string { return "`" + name + "`" }
+
+func (mysqlDialect) VersionQuery() string { return "select version();" }
+
+func (mysqlDialect) PlaceholderLimit() int { return 1<<16 - 1 } // 65,535
+
+func (mysqlDialect) LikeOperator() string { return "LIKE" }
+
+// CaseSensitiveLikeOperator forces byte-wise comparison, since MySQL's
+// default collation makes plain LIKE case-insensitive.
+func (mysqlDialect) CaseSensitiveLikeOperator() string { return "LIKE BINARY" }
+
+func (mysqlDialect) BuildLimitOffset(limit, offset int) string {
+	if limit < 0 {
+		if offset <= 0 {
+			return ""
+		}
+		// mysql requires a limit clause before offset; 2^64-1 means "no cap".
+		return fmt.Sprintf(" limit 18446744073709551615 offset %d", offset)
+	}
+	if offset <= 0 {
+		return fmt.Sprintf(" limit %d", limit)
+	}
+	return fmt.Sprintf(" limit %d offset %d", limit, offset)
+}
+
+func (mysqlDialect) BuildInsertIgnore(table string, cols, placeholders, _ []string) string {
+	return fmt.Sprintf(`insert ignore into %s(%s) values %s;`,
+		table,
+		strings.Join(cols, ","),
+		strings.Join(placeholders, ","),
+	)
+}
+
+func (d mysqlDialect) CreateTableSQL(table string, cols []ColumnDef) string {
+	defs := make([]string, 0, len(cols))
+	for _, col := range cols {
+		defs = append(defs, renderColumnDef(d, col))
+	}
+	return fmt.Sprintf("create table if not exists %s (%s);", table, strings.Join(defs, ", "))
+}
+
+func (d mysqlDialect) AddColumnSQL(table string, col ColumnDef) string {
+	return fmt.Sprintf("alter table %s add column %s;", table, renderColumnDef(d, col))
+}
+
+func (mysqlDialect) CreateIndexSQL(table, indexName string, cols []string, unique bool) string {
+	kind := "index"
+	if unique {
+		kind = "unique index"
+	}
+	return fmt.Sprintf("create %s %s on %s (%s);", kind, indexName, table, strings.Join(cols, ","))
+}
+
+func (mysqlDialect) ExistingColumnsQuery(table string) (string, []interface{}) {
+	return "select column_name, data_type from information_schema.columns where table_schema = database() and table_name = ?",
+		[]interface{}{table}
+}
+
+func (mysqlDialect) ExistingIndexesQuery(table string) (string, []interface{}) {
+	return "select distinct index_name from information_schema.statistics where table_schema = database() and table_name = ?",
+		[]interface{}{table}
+}
+
+func (mysqlDialect) BuildUpsert(table string, cols, placeholders, keys []string) string {
+	updates := make([]string, 0, len(cols))
+	for _, col := range cols {
+		updates = append(updates, fmt.Sprintf("%s=values(%s)", col, col))
+	}
+	return fmt.Sprintf(`insert into %s(%s) values %s on duplicate key update %s;`,
+		table,
+		strings.Join(cols, ","),
+		strings.Join(placeholders, ","),
+		strings.Join(updates, ","),
+	)
+}
@@ -0,0 +1,114 @@
+package dal
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Logger receives one record, successful or not, for every Exec, Query or
+// Prepare round-trip issued by Model.SQL, Create/Update, Read/Iterate/Stream,
+// ScanAll/ScanOne, Cleanup and DBInfo. QuerySet terminators, Sync/PlanSync,
+// MigrateUp and transaction begin/commit are not instrumented.
+type Logger interface {
+	LogQuery(ctx context.Context, query string, args []interface{}, duration time.Duration, rows int64, err error)
+}
+
+// SlowQueryLogger is an optional extension of Logger: when a round-trip's
+// duration meets or exceeds Model.SlowQueryThreshold, LogSlowQuery is
+// called instead of LogQuery, letting the implementation escalate the log
+// level (e.g. from debug to warn).
+type SlowQueryLogger interface {
+	Logger
+	LogSlowQuery(ctx context.Context, query string, args []interface{}, duration time.Duration, rows int64, err error)
+}
+
+// Span is the handle returned by Tracer.Start for a single SQL round-trip.
+type Span interface {
+	// SetAttribute records one span attribute; dal sets "db.system",
+	// "db.statement", and "db.rows_affected" on every span.
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Tracer opens one span around each Exec/Query/Prepare call that Logger
+// covers. Its shape
+// mirrors go.opentelemetry.io/otel/trace.Tracer.Start, so an OpenTelemetry
+// SDK tracer can implement it with a thin adapter.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Metrics receives Prometheus-style counters and latency histograms for
+// the same round-trips Logger covers, labeled by (table, op, status).
+type Metrics interface {
+	// IncQueries increments the query counter for (table, op, status).
+	// status is either "ok" or "error". table is "" for statements with
+	// no single table, e.g. DBInfo.
+	IncQueries(table, op, status string)
+	// ObserveLatency records one round-trip's duration for (table, op).
+	ObserveLatency(table, op string, duration time.Duration)
+}
+
+// StdLogger is a Logger/SlowQueryLogger backed by the standard library
+// "log" package; a convenient default while wiring up a new Model.
+type StdLogger struct{}
+
+func (StdLogger) LogQuery(ctx context.Context, query string, args []interface{}, duration time.Duration, rows int64, err error) {
+	if err != nil {
+		log.Printf("dal: query failed in %s: %v (query=%q args=%v)", duration, err, query, args)
+		return
+	}
+	log.Printf("dal: query ok in %s, %d rows (query=%q)", duration, rows, query)
+}
+
+func (StdLogger) LogSlowQuery(ctx context.Context, query string, args []interface{}, duration time.Duration, rows int64, err error) {
+	log.Printf("dal: SLOW query in %s, %d rows, err=%v (query=%q args=%v)", duration, rows, err, query, args)
+}
+
+// instrument wraps a single Exec/Query/Prepare round-trip with the Model's
+// Logger, Tracer and Metrics hooks. op is a short verb ("Exec", "Query",
+// "Prepare"); table may be "" for statements with no single table. fn
+// performs the actual round-trip and reports the rows it affected or
+// returned; it receives the (possibly span-carrying) ctx returned by
+// Tracer.Start so the round-trip itself runs under the new span, not the
+// one instrument was called with.
+func (model Model) instrument(ctx context.Context, op, table, query string, args []interface{}, fn func(ctx context.Context) (rows int64, err error)) (int64, error) {
+	var span Span
+	if model.Tracer != nil {
+		ctx, span = model.Tracer.Start(ctx, "dal."+op)
+	}
+
+	start := time.Now()
+	rows, err := fn(ctx)
+	duration := time.Since(start)
+
+	if span != nil {
+		span.SetAttribute("db.system", model.DriverName)
+		span.SetAttribute("db.statement", query)
+		span.SetAttribute("db.rows_affected", rows)
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}
+
+	if model.Metrics != nil {
+		status := "ok"
+		if err != nil {
+			status = "error"
+		}
+		model.Metrics.IncQueries(table, op, status)
+		model.Metrics.ObserveLatency(table, op, duration)
+	}
+
+	if model.Logger != nil {
+		if sl, ok := model.Logger.(SlowQueryLogger); ok && model.SlowQueryThreshold > 0 && duration >= model.SlowQueryThreshold {
+			sl.LogSlowQuery(ctx, query, args, duration, rows, err)
+		} else {
+			model.Logger.LogQuery(ctx, query, args, duration, rows, err)
+		}
+	}
+	return rows, err
+}
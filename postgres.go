@@ -0,0 +1,98 @@
+package dal
+
+import (
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// ---- PostgreSQL ----
+// Upsert uses ON CONFLICT, so the upserted row's conflict target (normally
+// its primary key) must be known; callers should supply the real key
+// column(s) (e.g. via the `pk` struct tag). BuildUpsert falls back to the
+// first column only as a last resort when keys is empty.
+
+type postgresDialect struct{}
+
+func init() { registerDialect(postgresDialect{}) }
+
+func (postgresDialect) Name() string { return "postgres" }
+
+func (postgresDialect) Placeholder(argIndex int) string { return fmt.Sprintf("$%d", argIndex) }
+
+func (postgresDialect) QuoteIdent(name string) string { return `"` + name + `"` }
+
+func (postgresDialect) VersionQuery() string { return "select version();" }
+
+func (postgresDialect) PlaceholderLimit() int { return 32767 }
+
+func (postgresDialect) LikeOperator() string { return "ILIKE" }
+
+// CaseSensitiveLikeOperator: Postgres' plain LIKE is already case-sensitive.
+func (postgresDialect) CaseSensitiveLikeOperator() string { return "LIKE" }
+
+func (postgresDialect) BuildLimitOffset(limit, offset int) string {
+	clause := ""
+	if limit >= 0 {
+		clause += fmt.Sprintf(" limit %d", limit)
+	}
+	if offset > 0 {
+		clause += fmt.Sprintf(" offset %d", offset)
+	}
+	return clause
+}
+
+func (postgresDialect) BuildInsertIgnore(table string, cols, placeholders, _ []string) string {
+	return fmt.Sprintf(`insert into %s(%s) values %s on conflict do nothing;`,
+		table,
+		strings.Join(cols, ","),
+		strings.Join(placeholders, ","),
+	)
+}
+
+func (d postgresDialect) CreateTableSQL(table string, cols []ColumnDef) string {
+	defs := make([]string, 0, len(cols))
+	for _, col := range cols {
+		defs = append(defs, renderColumnDef(d, col))
+	}
+	return fmt.Sprintf("create table if not exists %s (%s);", table, strings.Join(defs, ", "))
+}
+
+func (d postgresDialect) AddColumnSQL(table string, col ColumnDef) string {
+	return fmt.Sprintf("alter table %s add column %s;", table, renderColumnDef(d, col))
+}
+
+func (postgresDialect) CreateIndexSQL(table, indexName string, cols []string, unique bool) string {
+	kind := "index"
+	if unique {
+		kind = "unique index"
+	}
+	return fmt.Sprintf("create %s if not exists %s on %s (%s);", kind, indexName, table, strings.Join(cols, ","))
+}
+
+func (postgresDialect) ExistingColumnsQuery(table string) (string, []interface{}) {
+	return "select column_name, data_type from information_schema.columns where table_name = $1",
+		[]interface{}{table}
+}
+
+func (postgresDialect) ExistingIndexesQuery(table string) (string, []interface{}) {
+	return "select indexname from pg_indexes where tablename = $1", []interface{}{table}
+}
+
+func (postgresDialect) BuildUpsert(table string, cols, placeholders, keys []string) string {
+	if len(keys) == 0 {
+		keys = cols[:1]
+	}
+	updates := make([]string, 0, len(cols))
+	for _, col := range cols {
+		updates = append(updates, fmt.Sprintf("%s=excluded.%s", col, col))
+	}
+	return fmt.Sprintf(`insert into %s(%s) values %s on conflict (%s) do update set %s;`,
+		table,
+		strings.Join(cols, ","),
+		strings.Join(placeholders, ","),
+		strings.Join(keys, ","),
+		strings.Join(updates, ","),
+	)
+}
@@ -0,0 +1,214 @@
+package dal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// QuerySet builds a single SELECT/UPDATE/DELETE statement through a chain of
+// Filter/Exclude/OrderBy/Limit/Offset calls, inspired by beego-orm's
+// QuerySet. It never string-interpolates caller values: every Filter/Exclude
+// argument is bound through the Model's Dialect placeholders.
+type QuerySet struct {
+	model   *Model
+	table   string
+	cond    *Cond
+	order   []string
+	limitN  int
+	offsetN int
+}
+
+// QueryTable starts a QuerySet against table.
+func (model *Model) QueryTable(table string) *QuerySet {
+	return &QuerySet{model: model, table: table, limitN: -1}
+}
+
+// Filter ANDs a "field__lookup" condition onto the QuerySet, e.g.
+// Filter("age__gte", 18) or Filter("name", "bob") (lookup defaults to exact).
+func (qs *QuerySet) Filter(lookup string, args ...interface{}) *QuerySet {
+	qs.cond = andCond(qs.cond, Q(lookup, args...))
+	return qs
+}
+
+// Exclude ANDs the negation of a "field__lookup" condition onto the QuerySet.
+func (qs *QuerySet) Exclude(lookup string, args ...interface{}) *QuerySet {
+	qs.cond = andCond(qs.cond, Q(lookup, args...).Not())
+	return qs
+}
+
+// FilterCond ANDs an arbitrary Cond tree onto the QuerySet, for callers that
+// need OR/NOT combinations beyond what Filter/Exclude express.
+func (qs *QuerySet) FilterCond(cond *Cond) *QuerySet {
+	qs.cond = andCond(qs.cond, cond)
+	return qs
+}
+
+// OrderBy sets the ORDER BY columns; prefix a column with "-" for DESC.
+func (qs *QuerySet) OrderBy(fields ...string) *QuerySet {
+	qs.order = fields
+	return qs
+}
+
+// Limit caps the number of returned rows. A negative limit means no cap.
+func (qs *QuerySet) Limit(n int) *QuerySet {
+	qs.limitN = n
+	return qs
+}
+
+// Offset skips the first n matched rows.
+func (qs *QuerySet) Offset(n int) *QuerySet {
+	qs.offsetN = n
+	return qs
+}
+
+func andCond(root, next *Cond) *Cond {
+	if root == nil {
+		return next
+	}
+	return root.And(next)
+}
+
+// whereSQL renders the QuerySet's condition tree starting at placeholder
+// argIndex, returning the leading " where ..." clause (or "" if unfiltered).
+func (qs *QuerySet) whereSQL(argIndex *int) (string, []interface{}) {
+	if qs.cond == nil {
+		return "", nil
+	}
+	clause, args := qs.cond.toSQL(qs.model.dialect, argIndex)
+	return " where " + clause, args
+}
+
+func (qs *QuerySet) orderSQL() string {
+	if len(qs.order) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(qs.order))
+	for _, field := range qs.order {
+		if strings.HasPrefix(field, "-") {
+			parts = append(parts, field[1:]+" desc")
+		} else {
+			parts = append(parts, field)
+		}
+	}
+	return " order by " + strings.Join(parts, ",")
+}
+
+// All runs the SELECT and decodes matched rows into *dst, a pointer to a
+// slice of struct; column selection uses the same cached decode plan (and
+// `field` tag / embedded-struct rules) as ScanAll.
+func (qs *QuerySet) All(ctx context.Context, dst interface{}) (err error) {
+	conn, err := qs.model.getConn()
+	if err != nil {
+		return fmt.Errorf("%v\n dal.QuerySet.All failed on model.getConn", err)
+	}
+
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("dal.QuerySet.All: dst must be a pointer to a slice")
+	}
+	tp := dv.Elem().Type().Elem()
+	plan := planFor(tp)
+
+	argIndex := 1
+	where, args := qs.whereSQL(&argIndex)
+	query := fmt.Sprintf("select %s from %s%s%s%s",
+		strings.Join(plan.columns, ","), qs.table, where, qs.orderSQL(), qs.model.dialect.BuildLimitOffset(qs.limitN, qs.offsetN))
+
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%v\n dal.QuerySet.All failed on conn.QueryContext, query: %s", err, query)
+	}
+	defer rows.Close()
+
+	slice := dv.Elem()
+	for rows.Next() {
+		values := make([]interface{}, len(plan.index))
+		for i, path := range plan.index {
+			values[i] = reflect.New(reflect.PtrTo(tp.FieldByIndex(path).Type)).Interface()
+		}
+		if err := rows.Scan(values...); err != nil {
+			return fmt.Errorf("%v\n dal.QuerySet.All failed on rows.Scan", err)
+		}
+		elem := reflect.New(tp).Elem()
+		for i, path := range plan.index {
+			elem.FieldByIndex(path).Set(reflect.ValueOf(values[i]).Elem().Elem())
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+	return rows.Err()
+}
+
+// Count returns the number of rows matching the QuerySet.
+func (qs *QuerySet) Count(ctx context.Context) (count int64, err error) {
+	conn, err := qs.model.getConn()
+	if err != nil {
+		return 0, fmt.Errorf("%v\n dal.QuerySet.Count failed on model.getConn", err)
+	}
+	argIndex := 1
+	where, args := qs.whereSQL(&argIndex)
+	query := fmt.Sprintf("select count(*) from %s%s", qs.table, where)
+	if err = conn.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%v\n dal.QuerySet.Count failed on conn.QueryRowContext, query: %s", err, query)
+	}
+	return count, nil
+}
+
+// Exists reports whether any row matches the QuerySet.
+func (qs *QuerySet) Exists(ctx context.Context) (bool, error) {
+	count, err := qs.Count(ctx)
+	return count > 0, err
+}
+
+// Update sets the given columns on every row matching the QuerySet.
+func (qs *QuerySet) Update(ctx context.Context, values map[string]interface{}) (rowsAffected int64, err error) {
+	conn, err := qs.model.getConn()
+	if err != nil {
+		return 0, fmt.Errorf("%v\n dal.QuerySet.Update failed on model.getConn", err)
+	}
+	if len(values) == 0 {
+		return 0, fmt.Errorf("dal.QuerySet.Update: `values` has NO columns")
+	}
+
+	cols := make([]string, 0, len(values))
+	for col := range values {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols) // deterministic query text across calls
+
+	argIndex := 1
+	sets := make([]string, 0, len(cols))
+	args := make([]interface{}, 0, len(cols))
+	for _, col := range cols {
+		sets = append(sets, col+"="+qs.model.dialect.Placeholder(argIndex))
+		args = append(args, values[col])
+		argIndex++
+	}
+	where, whereArgs := qs.whereSQL(&argIndex)
+	args = append(args, whereArgs...)
+
+	query := fmt.Sprintf("update %s set %s%s", qs.table, strings.Join(sets, ","), where)
+	res, err := conn.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%v\n dal.QuerySet.Update failed on conn.ExecContext, query: %s", err, query)
+	}
+	return res.RowsAffected()
+}
+
+// Delete removes every row matching the QuerySet.
+func (qs *QuerySet) Delete(ctx context.Context) (rowsAffected int64, err error) {
+	conn, err := qs.model.getConn()
+	if err != nil {
+		return 0, fmt.Errorf("%v\n dal.QuerySet.Delete failed on model.getConn", err)
+	}
+	argIndex := 1
+	where, args := qs.whereSQL(&argIndex)
+	query := fmt.Sprintf("delete from %s%s", qs.table, where)
+	res, err := conn.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%v\n dal.QuerySet.Delete failed on conn.ExecContext, query: %s", err, query)
+	}
+	return res.RowsAffected()
+}
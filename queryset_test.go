@@ -0,0 +1,48 @@
+package dal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_QuerySet_render(t *testing.T) {
+	model := &Model{DriverName: "mysql", dialect: mysqlDialect{}}
+	qs := model.QueryTable("user").
+		Filter("age__gte", 18).
+		Filter("name__icontains", "bob").
+		Exclude("status__in", []string{"banned"}).
+		OrderBy("-created").
+		Limit(50).
+		Offset(100)
+
+	argIndex := 1
+	where, args := qs.whereSQL(&argIndex)
+	wantWhere := " where ((age >= ? AND name LIKE ?) AND NOT (status IN (?)))"
+	if where != wantWhere {
+		t.Errorf("where = %q, want %q", where, wantWhere)
+	}
+	if len(args) != 3 || args[0] != 18 || args[1] != "%bob%" || args[2] != "banned" {
+		t.Errorf("args = %+v", args)
+	}
+
+	if order := qs.orderSQL(); order != " order by created desc" {
+		t.Errorf("orderSQL = %q", order)
+	}
+	if limit := qs.model.dialect.BuildLimitOffset(qs.limitN, qs.offsetN); limit != " limit 50 offset 100" {
+		t.Errorf("limit/offset = %q", limit)
+	}
+}
+
+func Test_QuerySet_All_embedded_columns(t *testing.T) {
+	type being struct {
+		Name string `field:"name"`
+	}
+	type person struct {
+		being
+		Age int `field:"age"`
+	}
+	plan := planFor(reflect.TypeOf(person{}))
+	if len(plan.columns) != 2 || plan.columns[0] != "name" || plan.columns[1] != "age" {
+		t.Errorf("got %+v, want [name age] (All's column selection must descend into embedded structs like parseValue/ScanAll do)", plan.columns)
+	}
+}
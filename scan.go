@@ -0,0 +1,130 @@
+package dal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// decodePlan is a compiled column-order -> struct-field-path mapping for one
+// Go struct type T, built once per T by planFor and reused by every
+// subsequent ScanAll[T]/ScanOne[T] call.
+type decodePlan struct {
+	columns []string // column names, in struct field order
+	index   [][]int  // reflect.Value.FieldByIndex path per column, for embedded-struct support
+}
+
+// decodePlans caches one *decodePlan per struct type seen by ScanAll/ScanOne.
+var decodePlans sync.Map // map[reflect.Type]*decodePlan
+
+// planFor returns the cached decodePlan for tp, building and caching one on
+// first use. It walks nested structs like parseValue, except that a
+// time.Time field is kept as one column rather than walked into, and honors
+// a `field:"col,omitempty"` tag the same way parseValue honors `field:"col"`
+// (the ",omitempty" suffix is stripped from the column name and otherwise
+// ignored by reads).
+func planFor(tp reflect.Type) *decodePlan {
+	if cached, ok := decodePlans.Load(tp); ok {
+		return cached.(*decodePlan)
+	}
+
+	plan := &decodePlan{}
+	var walk func(t reflect.Type, prefix []int)
+	walk = func(t reflect.Type, prefix []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			path := append(append([]int{}, prefix...), i)
+			if f.Type.Kind() == reflect.Struct && f.Type != timeType {
+				walk(f.Type, path)
+				continue
+			}
+
+			tag := f.Tag.Get("field")
+			if comma := strings.IndexByte(tag, ','); comma >= 0 {
+				tag = tag[:comma]
+			}
+			if tag == "" {
+				tag = f.Name
+			}
+			plan.columns = append(plan.columns, tag)
+			plan.index = append(plan.index, path)
+		}
+	}
+	walk(tp, nil)
+
+	actual, _ := decodePlans.LoadOrStore(tp, plan)
+	return actual.(*decodePlan)
+}
+
+// scanRow decodes one row of rows into a freshly allocated T using plan,
+// addressing each target field directly; a pointer field (nullable column)
+// or a field implementing sql.Scanner is handled by database/sql itself,
+// the same way it would be for a Scan call built by hand.
+func scanRow[T any](rows *sql.Rows, plan *decodePlan) (T, error) {
+	var row T
+	rv := reflect.ValueOf(&row).Elem()
+	dest := make([]interface{}, len(plan.index))
+	for i, path := range plan.index {
+		dest[i] = rv.FieldByIndex(path).Addr().Interface()
+	}
+	err := rows.Scan(dest...)
+	return row, err
+}
+
+// ScanAll runs a query against table and decodes every matched row into a
+// []T, using the decode plan cached per T (the same plan Iterator.Scan
+// uses), so no per-field reflect.New(reflect.PtrTo(...)) is allocated per
+// row. T must be a struct; its columns follow planFor's `field` tag and
+// struct-descent rules.
+func ScanAll[T any](ctx context.Context, m *Model, table string, cond string) ([]T, error) {
+	var zero T
+	tp := reflect.TypeOf(zero)
+	if tp == nil || tp.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("dal.ScanAll: T must be a struct")
+	}
+	plan := planFor(tp)
+
+	conn, err := m.getConn()
+	if err != nil {
+		return nil, fmt.Errorf("%v\n dal.ScanAll failed on model.getConn", err)
+	}
+
+	query := fmt.Sprintf("select %s from %s %s", strings.Join(plan.columns, ","), table, cond)
+	var rows *sql.Rows
+	if _, err = m.instrument(ctx, "Query", table, query, nil, func(ctx context.Context) (int64, error) {
+		var queryErr error
+		rows, queryErr = conn.QueryContext(ctx, query)
+		return 0, queryErr
+	}); err != nil {
+		return nil, fmt.Errorf("%v\n dal.ScanAll failed on conn.QueryContext", err)
+	}
+	defer rows.Close()
+
+	var out []T
+	for rows.Next() {
+		row, err := scanRow[T](rows, plan)
+		if err != nil {
+			return nil, fmt.Errorf("%v\n dal.ScanAll failed on rows.Scan", err)
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+// ScanOne is ScanAll narrowed to a single row; cond should apply its own
+// "limit 1" (dialect-specific) if the caller wants to avoid scanning past
+// the first match. It returns sql.ErrNoRows if cond matched nothing.
+func ScanOne[T any](ctx context.Context, m *Model, table string, cond string) (T, error) {
+	var zero T
+	rows, err := ScanAll[T](ctx, m, table, cond)
+	if err != nil {
+		return zero, err
+	}
+	if len(rows) == 0 {
+		return zero, sql.ErrNoRows
+	}
+	return rows[0], nil
+}
@@ -0,0 +1,85 @@
+package dal
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func Test_planFor(t *testing.T) {
+	type being struct {
+		Name string `field:"name"`
+	}
+	type person struct {
+		being
+		Age      int     `field:"age,omitempty"`
+		Nickname *string `field:"nickname"`
+	}
+
+	plan := planFor(reflect.TypeOf(person{}))
+	wantCols := []string{"name", "age", "nickname"}
+	if len(plan.columns) != len(wantCols) {
+		t.Fatalf("columns = %+v, want %+v", plan.columns, wantCols)
+	}
+	for i, col := range wantCols {
+		if plan.columns[i] != col {
+			t.Errorf("columns[%d] = %q, want %q", i, plan.columns[i], col)
+		}
+	}
+
+	if cached := planFor(reflect.TypeOf(person{})); cached != plan {
+		t.Error("planFor did not reuse the cached plan on the second call")
+	}
+}
+
+func Test_ScanAll(t *testing.T) {
+	model := Model{
+		DriverName:     "mysql",
+		DataSourceName: "test@tcp(localhost)/test",
+	}
+	defer func() {
+		if err := model.SQL("drop table `scan_user`;"); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	if err := model.SQL("create table scan_user (id int primary key, name varchar(64));"); err != nil {
+		t.Error(err)
+		return
+	}
+	type T struct {
+		ID   int    `field:"id"`
+		Name string `field:"name"`
+	}
+	values := []T{
+		{1, "a"},
+		{2, "b"},
+	}
+	if _, err := model.Update("scan_user", values); err != nil {
+		t.Error(err)
+		return
+	}
+
+	rows, err := ScanAll[T](context.Background(), &model, "scan_user", "order by id")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(rows) != len(values) {
+		t.Errorf("len(rows) = %d, want %d", len(rows), len(values))
+	}
+	for i, row := range rows {
+		if row != values[i] {
+			t.Errorf("rows[%d] = %+v, want %+v", i, row, values[i])
+		}
+	}
+
+	one, err := ScanOne[T](context.Background(), &model, "scan_user", "where id = 1")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if one != values[0] {
+		t.Errorf("ScanOne = %+v, want %+v", one, values[0])
+	}
+}
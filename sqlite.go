@@ -0,0 +1,99 @@
+package dal
+
+import (
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// ---- SQLite ----
+
+type sqliteDialect struct{}
+
+func init() { registerDialect(sqliteDialect{}) }
+
+func (sqliteDialect) Name() string { return "sqlite3" }
+
+func (sqliteDialect) Placeholder(int) string { return "?" }
+
+func (sqliteDialect) QuoteIdent(name string) string { return `"` + name + `"` }
+
+func (sqliteDialect) VersionQuery() string { return "select sqlite_version();" }
+
+func (sqliteDialect) PlaceholderLimit() int { return 999 }
+
+func (sqliteDialect) LikeOperator() string { return "LIKE" }
+
+// CaseSensitiveLikeOperator: SQLite's LIKE is case-insensitive for ASCII by
+// default (PRAGMA case_sensitive_like off); GLOB is case-sensitive but uses
+// "*"/"?" wildcards instead of LIKE's "%"/"_", so it can't be substituted
+// here without rewriting the pattern. Callers needing strict case
+// sensitivity on SQLite should issue "PRAGMA case_sensitive_like = ON;".
+func (sqliteDialect) CaseSensitiveLikeOperator() string { return "LIKE" }
+
+func (sqliteDialect) BuildLimitOffset(limit, offset int) string {
+	clause := ""
+	if limit >= 0 {
+		clause += fmt.Sprintf(" limit %d", limit)
+	}
+	if offset > 0 {
+		clause += fmt.Sprintf(" offset %d", offset)
+	}
+	return clause
+}
+
+func (sqliteDialect) BuildInsertIgnore(table string, cols, placeholders, _ []string) string {
+	return fmt.Sprintf(`insert or ignore into %s(%s) values %s;`,
+		table,
+		strings.Join(cols, ","),
+		strings.Join(placeholders, ","),
+	)
+}
+
+func (d sqliteDialect) CreateTableSQL(table string, cols []ColumnDef) string {
+	defs := make([]string, 0, len(cols))
+	for _, col := range cols {
+		defs = append(defs, renderColumnDef(d, col))
+	}
+	return fmt.Sprintf("create table if not exists %s (%s);", table, strings.Join(defs, ", "))
+}
+
+func (d sqliteDialect) AddColumnSQL(table string, col ColumnDef) string {
+	return fmt.Sprintf("alter table %s add column %s;", table, renderColumnDef(d, col))
+}
+
+func (sqliteDialect) CreateIndexSQL(table, indexName string, cols []string, unique bool) string {
+	kind := "index"
+	if unique {
+		kind = "unique index"
+	}
+	return fmt.Sprintf("create %s if not exists %s on %s (%s);", kind, indexName, table, strings.Join(cols, ","))
+}
+
+func (sqliteDialect) ExistingColumnsQuery(table string) (string, []interface{}) {
+	// pragma_table_info is a table-valued function; an unknown table just
+	// yields zero rows rather than an error.
+	return "select name, type from pragma_table_info(?)", []interface{}{table}
+}
+
+func (sqliteDialect) ExistingIndexesQuery(table string) (string, []interface{}) {
+	return "select name from sqlite_master where type = 'index' and tbl_name = ?", []interface{}{table}
+}
+
+func (sqliteDialect) BuildUpsert(table string, cols, placeholders, keys []string) string {
+	if len(keys) == 0 {
+		keys = cols[:1]
+	}
+	updates := make([]string, 0, len(cols))
+	for _, col := range cols {
+		updates = append(updates, fmt.Sprintf("%s=excluded.%s", col, col))
+	}
+	return fmt.Sprintf(`insert into %s(%s) values %s on conflict(%s) do update set %s;`,
+		table,
+		strings.Join(cols, ","),
+		strings.Join(placeholders, ","),
+		strings.Join(keys, ","),
+		strings.Join(updates, ","),
+	)
+}
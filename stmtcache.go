@@ -0,0 +1,128 @@
+package dal
+
+import (
+	"container/list"
+	"database/sql"
+	"sync"
+)
+
+// defaultStmtCacheSize bounds how many distinct prepared statements
+// (keyed by connection, table, mode and batch size) are kept live at once.
+const defaultStmtCacheSize = 128
+
+// stmtKey identifies one distinct prepared statement shape: a given
+// dialect+table+mode combination prepares a different query per batch size
+// (the number of placeholder groups it binds), so batchSize is part of the
+// key. columns (the caller's tag list, joined) is also part of the key:
+// two struct types writing to the same table can have different column
+// counts/order while still colliding on table+mode+batchSize, and sharing a
+// prepared statement across them would silently bind values into the wrong
+// columns.
+type stmtKey struct {
+	connKey   string
+	table     string
+	mode      string
+	batchSize int
+	columns   string
+}
+
+// stmtEntry is a cache slot for one prepared statement. refs tracks how
+// many in-flight Exec calls are currently using stmt (via acquire/release);
+// an entry that has been evicted or superseded is only Close'd once its
+// last reference is released, so a concurrent write() can never be handed
+// an already-closed *sql.Stmt.
+type stmtEntry struct {
+	key     stmtKey
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	retired bool
+}
+
+type stmtCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[stmtKey]*list.Element
+}
+
+func newStmtCache(capacity int) *stmtCache {
+	return &stmtCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[stmtKey]*list.Element),
+	}
+}
+
+// acquire returns the cached entry for key, if any, marking it
+// most-recently-used and incrementing its reference count. The caller must
+// call release(entry) exactly once it is done using entry.stmt.
+func (c *stmtCache) acquire(key stmtKey) (*stmtEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	entry := el.Value.(*stmtEntry)
+	entry.refs++
+	return entry, true
+}
+
+// release drops one reference acquired via acquire or put. If entry has
+// since been evicted or superseded and this was its last reference, its
+// *sql.Stmt is closed now.
+func (c *stmtCache) release(entry *stmtEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.refs--
+	if entry.retired && entry.refs == 0 {
+		entry.stmt.Close()
+	}
+}
+
+// put stores stmt (prepared for query) under key with one reference
+// already held on the caller's behalf (release it once done), evicting the
+// least-recently-used entry if the cache is over capacity, or superseding
+// an existing entry for the same key. An evicted/superseded entry's
+// *sql.Stmt is closed immediately if unreferenced, or once its last
+// reference is released otherwise.
+func (c *stmtCache) put(key stmtKey, query string, stmt *sql.Stmt) *stmtEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &stmtEntry{key: key, query: query, stmt: stmt, refs: 1}
+	if el, ok := c.items[key]; ok {
+		c.retireLocked(el.Value.(*stmtEntry))
+		el.Value = entry
+		c.ll.MoveToFront(el)
+		return entry
+	}
+
+	el := c.ll.PushFront(entry)
+	c.items[key] = el
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		old := oldest.Value.(*stmtEntry)
+		delete(c.items, old.key)
+		c.retireLocked(old)
+	}
+	return entry
+}
+
+// retireLocked marks entry as no longer reachable from the cache, closing
+// its *sql.Stmt immediately if nothing still holds a reference via
+// acquire/put, or deferring the close to the matching release otherwise.
+// c.mu must be held.
+func (c *stmtCache) retireLocked(entry *stmtEntry) {
+	entry.retired = true
+	if entry.refs == 0 {
+		entry.stmt.Close()
+	}
+}
+
+// globalStmtCache is shared by every Model.write call; entries are scoped by
+// connKey so statements from one *sql.DB are never reused against another.
+var globalStmtCache = newStmtCache(defaultStmtCacheSize)
@@ -0,0 +1,93 @@
+package dal
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+// Test_stmtCache_acquireReleaseRetire exercises the LRU eviction and
+// same-key-overwrite paths while a reference is still held, verifying the
+// underlying *sql.Stmt is only Close'd once it is both retired (evicted or
+// superseded) and unreferenced, never while an Exec might still be using it.
+func Test_stmtCache_acquireReleaseRetire(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Skip("no sqlite3 driver available:", err)
+	}
+	defer db.Close()
+
+	prepare := func(t *testing.T) *sql.Stmt {
+		stmt, err := db.Prepare("select 1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stmt
+	}
+
+	cache := newStmtCache(1)
+	k1 := stmtKey{connKey: "c", table: "t", mode: "Create", batchSize: 1}
+	k2 := stmtKey{connKey: "c", table: "t", mode: "Create", batchSize: 2}
+
+	// Hold a reference to k1 across its eviction by k2 (capacity is 1).
+	entry1 := cache.put(k1, "select 1", prepare(t))
+	origEntry2 := cache.put(k2, "select 1", prepare(t))
+	cache.release(origEntry2)
+
+	if _, err := entry1.stmt.Query(); err != nil {
+		t.Errorf("evicted-but-still-referenced stmt should still be usable, got: %v", err)
+	}
+	cache.release(entry1)
+	if _, err := entry1.stmt.Query(); err == nil {
+		t.Error("stmt should be closed once its last reference is released after retirement")
+	}
+
+	// Overwriting an existing key behaves the same way: the old entry is
+	// only closed once whoever still holds it releases.
+	entry2, ok := cache.acquire(k2)
+	if !ok {
+		t.Fatal("expected k2 to still be cached")
+	}
+	cache.put(k2, "select 1", prepare(t))
+	if _, err := entry2.stmt.Query(); err != nil {
+		t.Errorf("superseded-but-still-referenced stmt should still be usable, got: %v", err)
+	}
+	cache.release(entry2)
+	if _, err := entry2.stmt.Query(); err == nil {
+		t.Error("superseded stmt should be closed once its last reference is released")
+	}
+}
+
+// Benchmark_write_batchUpsert exercises model.write with values of mixed Go
+// types (int and string columns), with enough iterations that every
+// batch after the first hits the prepared-statement cache. Run against a
+// live MySQL instance, e.g.:
+//
+//	go test -run=NONE -bench=Benchmark_write_batchUpsert -benchtime=200x
+func Benchmark_write_batchUpsert(b *testing.B) {
+	model := Model{
+		DriverName:     "mysql",
+		DataSourceName: "test@tcp(localhost)/test",
+	}
+	if err := model.SQL("create table if not exists bench_user (id int primary key, name varchar(64), age int);"); err != nil {
+		b.Skip("no live database available:", err)
+	}
+	defer model.SQL("drop table bench_user;")
+
+	type T struct {
+		ID   int    `field:"id"`
+		Name string `field:"name"`
+		Age  int    `field:"age"`
+	}
+	values := make([]T, 200)
+	for i := range values {
+		values[i] = T{ID: i, Name: fmt.Sprintf("user-%d", i), Age: i % 100}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := model.Update("bench_user", values); err != nil {
+			b.Fatal(err)
+		}
+	}
+}